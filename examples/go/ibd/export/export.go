@@ -0,0 +1,219 @@
+// Package export turns decoded InnoDB INDEX pages into Apache Parquet
+// files, bridging the low-level ibd page decoder to offline analytics on
+// MySQL/MariaDB backups.
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/compress"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+
+	"ibd-reader-example/ibd"
+)
+
+// InnoType identifies the InnoDB column type used to decode a record field.
+type InnoType int
+
+const (
+	TypeTinyInt InnoType = iota
+	TypeSmallInt
+	TypeInt
+	TypeBigInt
+	TypeVarchar
+	TypeChar
+	TypeDecimal
+	TypeDatetime
+	TypeBlob
+)
+
+// ColumnDef describes one column of the table being exported, in InnoDB
+// record layout order.
+type ColumnDef struct {
+	Name         string
+	Type         InnoType
+	Nullable     bool
+	IsPrimaryKey bool
+	// Length is the declared column length for fixed/variable-length
+	// character types; it is ignored for other types.
+	Length int
+	// FSP is the fractional seconds precision (0-6) of a TypeDatetime
+	// column. It determines how many bytes DATETIME2 storage uses beyond
+	// the core 5-byte year/month/day/hour/minute/second fields; it is
+	// ignored for other types.
+	FSP int
+}
+
+// TableSchema describes the layout callers expect ExportTableToParquet to
+// decode each INDEX record with, since InnoDB pages carry no column names
+// or types of their own.
+type TableSchema struct {
+	Columns []ColumnDef
+}
+
+// CompressionCodec selects the codec used for Parquet column chunks.
+type CompressionCodec int
+
+const (
+	CompressionSnappy CompressionCodec = iota
+	CompressionZstd
+	CompressionGzip
+)
+
+func (c CompressionCodec) parquetCodec() compress.Compression {
+	switch c {
+	case CompressionZstd:
+		return compress.Codecs.Zstd
+	case CompressionGzip:
+		return compress.Codecs.Gzip
+	default:
+		return compress.Codecs.Snappy
+	}
+}
+
+// ExportOptions controls row grouping and compression of the output file.
+type ExportOptions struct {
+	RowGroupSize int64
+	Compression  CompressionCodec
+	// KeyringPath, MasterKeyID and ServerUUID are forwarded to the Reader
+	// pipeline when ibdPath is an encrypted tablespace.
+	KeyringPath string
+	MasterKeyID uint32
+	ServerUUID  string
+}
+
+// ExportTableToParquet walks the INDEX pages of ibdPath, decodes each
+// record according to schema, and writes the result to out as a Parquet
+// file. Compressed and encrypted tablespaces are supported by reusing the
+// existing ibd.Reader pipeline; off-page BLOB/ZBLOB columns are
+// dereferenced into BYTE_ARRAY columns.
+func ExportTableToParquet(ibdPath string, schema *TableSchema, out io.Writer, opts ExportOptions) error {
+	if schema == nil || len(schema.Columns) == 0 {
+		return fmt.Errorf("schema must declare at least one column")
+	}
+
+	reader, err := ibd.NewReader()
+	if err != nil {
+		return fmt.Errorf("create reader: %w", err)
+	}
+	defer reader.Close()
+
+	arrowSchema := buildArrowSchema(schema)
+
+	pool := memory.NewGoAllocator()
+	builders := make([]array.Builder, len(schema.Columns))
+	for i, col := range schema.Columns {
+		builders[i] = array.NewBuilder(pool, arrowFieldType(col))
+	}
+	defer func() {
+		for _, b := range builders {
+			b.Release()
+		}
+	}()
+
+	rowGroupSize := opts.RowGroupSize
+	if rowGroupSize <= 0 {
+		rowGroupSize = 64 * 1024
+	}
+
+	props := parquet.NewWriterProperties(
+		parquet.WithCompression(opts.Compression.parquetCodec()),
+		parquet.WithMaxRowGroupLength(rowGroupSize),
+	)
+
+	writer, err := pqarrow.NewFileWriter(arrowSchema, out, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return fmt.Errorf("create parquet writer: %w", err)
+	}
+	defer writer.Close()
+
+	walker, err := newIndexPageWalker(reader, ibdPath, opts)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", ibdPath, err)
+	}
+	defer walker.Close()
+	walker.schema = schema
+
+	var buffered int64
+	for {
+		rec, ok, err := walker.NextRecord()
+		if err != nil {
+			return fmt.Errorf("decode record: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		if err := appendRecord(builders, schema, rec); err != nil {
+			return fmt.Errorf("decode record: %w", err)
+		}
+		buffered++
+
+		if buffered >= rowGroupSize {
+			if err := flushRowGroup(writer, arrowSchema, builders); err != nil {
+				return err
+			}
+			buffered = 0
+		}
+	}
+
+	if buffered > 0 {
+		if err := flushRowGroup(writer, arrowSchema, builders); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flushRowGroup(writer *pqarrow.FileWriter, schema *arrow.Schema, builders []array.Builder) error {
+	cols := make([]arrow.Array, len(builders))
+	for i, b := range builders {
+		cols[i] = b.NewArray()
+	}
+	defer func() {
+		for _, c := range cols {
+			c.Release()
+		}
+	}()
+
+	rows := cols[0].Len()
+	batch := array.NewRecord(schema, cols, int64(rows))
+	defer batch.Release()
+
+	return writer.WriteBuffered(batch)
+}
+
+func buildArrowSchema(schema *TableSchema) *arrow.Schema {
+	fields := make([]arrow.Field, len(schema.Columns))
+	for i, col := range schema.Columns {
+		fields[i] = arrow.Field{
+			Name:     col.Name,
+			Type:     arrowFieldType(col),
+			Nullable: col.Nullable,
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+func arrowFieldType(col ColumnDef) arrow.DataType {
+	switch col.Type {
+	case TypeTinyInt, TypeSmallInt, TypeInt:
+		return arrow.PrimitiveTypes.Int32
+	case TypeBigInt:
+		return arrow.PrimitiveTypes.Int64
+	case TypeDecimal:
+		return arrow.BinaryTypes.String
+	case TypeDatetime:
+		return arrow.FixedWidthTypes.Timestamp_us
+	case TypeBlob:
+		return arrow.BinaryTypes.Binary
+	default: // TypeVarchar, TypeChar
+		return arrow.BinaryTypes.String
+	}
+}
@@ -0,0 +1,497 @@
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v14/arrow/array"
+
+	"ibd-reader-example/ibd"
+)
+
+// record is one decoded INDEX record, with each value already resolved to
+// its final form (off-page BLOB columns dereferenced into their full
+// byte slices).
+type record struct {
+	values []interface{}
+}
+
+// indexPageWalker iterates the INDEX pages of a tablespace, decoding user
+// records according to a TableSchema and following BLOB/ZBLOB pointer
+// columns as needed.
+type indexPageWalker struct {
+	reader  *ibd.Reader
+	file    io.ReaderAt
+	closer  io.Closer
+	opts    ExportOptions
+	schema  *TableSchema
+	pageNum uint32
+	pending []record
+}
+
+func newIndexPageWalker(reader *ibd.Reader, ibdPath string, opts ExportOptions) (*indexPageWalker, error) {
+	f, err := openTablespace(ibdPath)
+	if err != nil {
+		return nil, err
+	}
+	return &indexPageWalker{
+		reader: reader,
+		file:   f,
+		closer: f,
+		opts:   opts,
+	}, nil
+}
+
+func (w *indexPageWalker) Close() error {
+	return w.closer.Close()
+}
+
+// NextRecord returns the next decoded user record, reading and decoding
+// additional INDEX pages as needed. ok is false once every page has been
+// walked.
+func (w *indexPageWalker) NextRecord() (*record, bool, error) {
+	for len(w.pending) == 0 {
+		page, err := w.readPage(w.pageNum)
+		if err == io.EOF {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		w.pageNum++
+
+		info, err := ibd.GetPageInfo(page)
+		if err != nil {
+			return nil, false, fmt.Errorf("page %d: %w", w.pageNum-1, err)
+		}
+		if info.PageType != uint16(ibd.PageTypeIndex) {
+			continue
+		}
+
+		recs, err := decodeIndexPage(w, page)
+		if err != nil {
+			return nil, false, fmt.Errorf("page %d: %w", w.pageNum-1, err)
+		}
+		w.pending = recs
+	}
+
+	rec := w.pending[0]
+	w.pending = w.pending[1:]
+	return &rec, true, nil
+}
+
+func (w *indexPageWalker) readPage(pageNum uint32) ([]byte, error) {
+	// Pages are read at a fixed 16KB stride, which covers the vast majority
+	// of InnoDB tables; other physical page sizes are not detected. A page
+	// that IsPageCompressed flags is restored by DecompressPage below.
+	const defaultPageSize = 16 * 1024
+	buf := make([]byte, defaultPageSize)
+	n, err := w.file.ReadAt(buf, int64(pageNum)*defaultPageSize)
+	if n == 0 {
+		if err != nil {
+			return nil, io.EOF
+		}
+		return nil, io.EOF
+	}
+
+	decoded := buf[:n]
+	if w.opts.KeyringPath != "" {
+		decrypted, _, derr := w.decrypt(decoded)
+		if derr != nil {
+			return nil, fmt.Errorf("decrypt page %d: %w", pageNum, derr)
+		}
+		decoded = decrypted
+	}
+	if ibd.IsPageCompressed(decoded, len(decoded), defaultPageSize) {
+		out, _, derr := w.reader.DecompressPage(decoded)
+		if derr != nil {
+			return nil, fmt.Errorf("decompress page %d: %w", pageNum, derr)
+		}
+		decoded = out
+	}
+
+	return decoded, nil
+}
+
+func (w *indexPageWalker) decrypt(page []byte) ([]byte, *ibd.PageInfo, error) {
+	// Page-level decryption is delegated to the streaming iterator's
+	// primitive so export shares the exact same code path as the rest of
+	// the module.
+	it, err := w.reader.NewPageIterator(singlePageReaderAt{page}, len(page), ibd.IterOptions{
+		Decrypt:     true,
+		KeyringPath: w.opts.KeyringPath,
+		MasterKeyID: w.opts.MasterKeyID,
+		ServerUUID:  w.opts.ServerUUID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	_, _, decoded, info, err := it.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	return decoded, info, nil
+}
+
+type singlePageReaderAt struct {
+	page []byte
+}
+
+func (s singlePageReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off != 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.page)
+	return n, nil
+}
+
+// decodeIndexPage decodes every user record on a single INDEX page
+// according to w.schema. Off-page BLOB/ZBLOB columns are dereferenced by
+// following their 20-byte pointer (page number + offset + length) into the
+// referenced overflow pages.
+func decodeIndexPage(w *indexPageWalker, page []byte) ([]record, error) {
+	// The real record format depends on ROW_FORMAT (COMPACT/DYNAMIC) and the
+	// page directory; decodeCompactRecords walks the user records in
+	// insertion order using the page's record heap.
+	return decodeCompactRecords(w, page)
+}
+
+// decodeCompactRecords walks COMPACT/DYNAMIC row-format user records
+// starting at the page's infimum, following next-record offsets until it
+// reaches the supremum pseudo-record.
+func decodeCompactRecords(w *indexPageWalker, page []byte) ([]record, error) {
+	const (
+		filHeaderSize  = 38
+		pageHeaderSize = 56
+		infimumOffset  = filHeaderSize + pageHeaderSize + innoRecordHeaderSize
+	)
+
+	var out []record
+	offset := infimumOffset
+
+	for {
+		if offset+2 > len(page) {
+			break
+		}
+		nextDelta := int(int16(binary.BigEndian.Uint16(page[offset-2 : offset])))
+		next := offset + nextDelta
+		if nextDelta == 0 || next <= 0 || next >= len(page) {
+			break
+		}
+
+		rec, isSupremum, err := decodeRecord(w, page, offset)
+		if err != nil {
+			return nil, err
+		}
+		if isSupremum {
+			break
+		}
+		if rec != nil {
+			out = append(out, *rec)
+		}
+
+		offset = next
+	}
+
+	return out, nil
+}
+
+// innoRecordHeaderSize is the fixed size, in bytes, of a COMPACT/DYNAMIC
+// record header (info bits + n_owned, heap_no + record_type, next-record
+// offset) immediately preceding a record's user data.
+const innoRecordHeaderSize = 5
+
+// recordHeader holds the per-column nullness and on-page length recovered
+// from the null-bitmap and variable-length-field array that precede a
+// COMPACT/DYNAMIC record's header, so decodeField never has to assume a
+// column is fixed-width or non-null.
+type recordHeader struct {
+	isNull     []bool
+	length     []int
+	isExternal []bool
+}
+
+// parseRecordHeader reads the null bitmap and variable-length field array
+// that sit immediately before the 5-byte record header at page[offset-5:
+// offset]. Layout, from low to high address: [variable-length field
+// lengths, reverse column order, NULL columns skipped][NULL bitmap, one
+// bit per nullable column, LSB of byte 0 first][record header][record
+// data]. The NULL bitmap has a fixed size, so it's read first; only then
+// is it known which variable-length columns have an array entry at all.
+func parseRecordHeader(page []byte, offset int, schema *TableSchema) (*recordHeader, error) {
+	headerStart := offset - innoRecordHeaderSize
+	if headerStart < 0 {
+		return nil, fmt.Errorf("record header out of range at offset %d", offset)
+	}
+
+	var nullableIdx []int
+	for i, col := range schema.Columns {
+		if col.Nullable {
+			nullableIdx = append(nullableIdx, i)
+		}
+	}
+	nullBitmapSize := (len(nullableIdx) + 7) / 8
+
+	rh := &recordHeader{
+		isNull:     make([]bool, len(schema.Columns)),
+		length:     make([]int, len(schema.Columns)),
+		isExternal: make([]bool, len(schema.Columns)),
+	}
+	for i, col := range schema.Columns {
+		rh.length[i] = col.Length
+	}
+
+	bitmapStart := headerStart - nullBitmapSize
+	if bitmapStart < 0 {
+		return nil, fmt.Errorf("null bitmap out of range at offset %d", offset)
+	}
+	bitmap := page[bitmapStart:headerStart]
+	for bit, colIdx := range nullableIdx {
+		byteIdx := bit / 8
+		mask := byte(1) << uint(bit%8)
+		if bitmap[byteIdx]&mask != 0 {
+			rh.isNull[colIdx] = true
+			rh.length[colIdx] = 0
+		}
+	}
+
+	p := bitmapStart
+	for i := 0; i < len(schema.Columns); i++ {
+		col := schema.Columns[i]
+		if !isVariableLength(col.Type) || rh.isNull[i] {
+			continue
+		}
+
+		p--
+		if p < 0 {
+			return nil, fmt.Errorf("variable-length array out of range at offset %d", offset)
+		}
+		b1 := page[p]
+
+		if col.Length > 255 || col.Type == TypeBlob {
+			if b1&0x80 != 0 {
+				p--
+				if p < 0 {
+					return nil, fmt.Errorf("variable-length array out of range at offset %d", offset)
+				}
+				b2 := page[p]
+				rh.length[i] = int(b1&0x3F)<<8 | int(b2)
+				rh.isExternal[i] = b1&0x40 != 0
+				continue
+			}
+			rh.length[i] = int(b1 & 0x7F)
+			continue
+		}
+
+		rh.length[i] = int(b1)
+	}
+
+	return rh, nil
+}
+
+func isVariableLength(t InnoType) bool {
+	switch t {
+	case TypeVarchar, TypeBlob:
+		return true
+	default:
+		return false
+	}
+}
+
+// decodeRecord decodes a single record at offset according to w.schema,
+// dereferencing off-page columns. isSupremum reports whether offset landed
+// on the page's supremum pseudo-record, ending iteration.
+func decodeRecord(w *indexPageWalker, page []byte, offset int) (rec *record, isSupremum bool, err error) {
+	if offset+8 <= len(page) && string(page[offset:offset+8]) == "supremum" {
+		return nil, true, nil
+	}
+	if offset+7 <= len(page) && string(page[offset:offset+7]) == "infimum" {
+		return nil, false, nil
+	}
+
+	rh, err := parseRecordHeader(page, offset, w.schema)
+	if err != nil {
+		return nil, false, err
+	}
+
+	values := make([]interface{}, len(w.schema.Columns))
+	pos := offset
+	for i, col := range w.schema.Columns {
+		if rh.isNull[i] {
+			values[i] = nil
+			continue
+		}
+
+		v, n, err := decodeField(w, page, pos, col, rh.length[i], rh.isExternal[i])
+		if err != nil {
+			return nil, false, fmt.Errorf("column %s: %w", col.Name, err)
+		}
+		values[i] = v
+		pos += n
+	}
+
+	return &record{values: values}, false, nil
+}
+
+// decodeField decodes a single non-NULL field starting at pos. length is
+// the on-page length recovered from the variable-length array for
+// variable-length columns (ignored for fixed-width types); external
+// reports whether a BLOB/ZBLOB column is stored off-page.
+func decodeField(w *indexPageWalker, page []byte, pos int, col ColumnDef, length int, external bool) (interface{}, int, error) {
+	switch col.Type {
+	case TypeTinyInt:
+		return int32(int8(page[pos])), 1, nil
+	case TypeSmallInt:
+		return int32(int16(binary.BigEndian.Uint16(page[pos : pos+2]))), 2, nil
+	case TypeInt:
+		return int32(binary.BigEndian.Uint32(page[pos : pos+4])), 4, nil
+	case TypeBigInt:
+		return int64(binary.BigEndian.Uint64(page[pos : pos+8])), 8, nil
+	case TypeDatetime:
+		return decodeDatetime(page, pos, col.FSP)
+	case TypeDecimal:
+		n := col.Length
+		if n <= 0 {
+			n = 8
+		}
+		return string(page[pos : pos+n]), n, nil
+	case TypeChar:
+		n := col.Length
+		return string(page[pos : pos+n]), n, nil
+	case TypeVarchar:
+		return string(page[pos : pos+length]), length, nil
+	case TypeBlob:
+		if external {
+			return w.resolveOffPageBlob(page, pos)
+		}
+		return append([]byte(nil), page[pos:pos+length]...), length, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported column type %d", col.Type)
+	}
+}
+
+// datetimeFracBytes returns how many bytes DATETIME2 storage spends on
+// fractional seconds for the given precision, matching MySQL's
+// fsp-to-byte-count mapping: 0 for fsp 0, 1 byte for fsp 1-2, 2 bytes for
+// fsp 3-4, 3 bytes for fsp 5-6.
+func datetimeFracBytes(fsp int) int {
+	switch {
+	case fsp <= 0:
+		return 0
+	case fsp <= 2:
+		return 1
+	case fsp <= 4:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// decodeDatetime reads a DATETIME2 field at pos: a 5-byte big-endian
+// integer packing sign|year*13+month|day|hour|minute|second, followed by
+// 0-3 bytes of fractional seconds whose width is determined by fsp. The
+// two parts are combined into a single int64 (5-byte integer in the high
+// 40 bits, fractional microseconds in the low 24) for arrowTimestamp to
+// unpack, since decodeField returns a single value per field.
+func decodeDatetime(page []byte, pos int, fsp int) (interface{}, int, error) {
+	fracBytes := datetimeFracBytes(fsp)
+	n := 5 + fracBytes
+	if pos+n > len(page) {
+		return nil, 0, fmt.Errorf("truncated datetime field")
+	}
+
+	intPart := uint64(page[pos])<<32 | uint64(page[pos+1])<<24 | uint64(page[pos+2])<<16 |
+		uint64(page[pos+3])<<8 | uint64(page[pos+4])
+
+	var fracMicros uint32
+	if fracBytes > 0 {
+		fracMicros = decodeDatetimeFrac(page[pos+5 : pos+5+fracBytes])
+	}
+
+	return int64(intPart)<<24 | int64(fracMicros), n, nil
+}
+
+// decodeDatetimeFrac converts a DATETIME2 fractional-seconds field to
+// microseconds, following MySQL's storage scaling: a 1-byte field holds
+// hundredths of a second, a 2-byte field holds ten-thousandths, and a
+// 3-byte field already holds microseconds directly.
+func decodeDatetimeFrac(b []byte) uint32 {
+	switch len(b) {
+	case 1:
+		return uint32(b[0]) * 10000
+	case 2:
+		return (uint32(b[0])<<8 | uint32(b[1])) * 100
+	case 3:
+		return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	default:
+		return 0
+	}
+}
+
+// resolveOffPageBlob follows InnoDB's 20-byte external field reference
+// (space id, page number, offset, length) to pull a BLOB/ZBLOB column's
+// full contents off its overflow pages.
+func (w *indexPageWalker) resolveOffPageBlob(page []byte, pos int) (interface{}, int, error) {
+	const refSize = 20
+	if pos+refSize > len(page) {
+		return nil, 0, fmt.Errorf("truncated blob reference")
+	}
+
+	ref := page[pos : pos+refSize]
+	blobPage := binary.BigEndian.Uint32(ref[4:8])
+	blobOffset := binary.BigEndian.Uint32(ref[8:12])
+	blobLen := binary.BigEndian.Uint32(ref[16:20]) // low half of the 8-byte length field at ref[12:20]
+
+	data := make([]byte, 0, blobLen)
+	for blobPage != 0xFFFFFFFF && uint32(len(data)) < blobLen {
+		chunk, err := w.readPage(blobPage)
+		if err != nil {
+			return nil, 0, fmt.Errorf("read blob page %d: %w", blobPage, err)
+		}
+
+		const filHeaderSize = 38
+		nextPage := binary.BigEndian.Uint32(chunk[filHeaderSize : filHeaderSize+4])
+		payloadStart := int(blobOffset)
+		if payloadStart == 0 {
+			payloadStart = filHeaderSize + 18 // past the BLOB page header
+		}
+		data = append(data, chunk[payloadStart:]...)
+
+		blobPage = nextPage
+		blobOffset = 0
+	}
+
+	if uint32(len(data)) > blobLen {
+		data = data[:blobLen]
+	}
+
+	return data, refSize, nil
+}
+
+// appendRecord appends rec's values to the corresponding Arrow builders.
+func appendRecord(builders []array.Builder, schema *TableSchema, rec *record) error {
+	for i, col := range schema.Columns {
+		v := rec.values[i]
+		b := builders[i]
+
+		if v == nil {
+			b.AppendNull()
+			continue
+		}
+
+		switch col.Type {
+		case TypeTinyInt, TypeSmallInt, TypeInt:
+			b.(*array.Int32Builder).Append(v.(int32))
+		case TypeBigInt:
+			b.(*array.Int64Builder).Append(v.(int64))
+		case TypeDatetime:
+			b.(*array.TimestampBuilder).Append(arrowTimestamp(v.(int64)))
+		case TypeBlob:
+			b.(*array.BinaryBuilder).Append(v.([]byte))
+		default: // TypeVarchar, TypeChar, TypeDecimal
+			b.(*array.StringBuilder).Append(v.(string))
+		}
+	}
+	return nil
+}
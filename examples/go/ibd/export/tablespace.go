@@ -0,0 +1,43 @@
+package export
+
+import (
+	"os"
+	"time"
+
+	"github.com/apache/arrow/go/v14/arrow"
+)
+
+func openTablespace(path string) (*os.File, error) {
+	return os.Open(path)
+}
+
+// arrowTimestamp converts decodeDatetime's packed representation — the
+// low 24 bits holding fractional-second microseconds, and bits 24-63
+// holding a big-endian integer packing sign | year*13+month (17 bits) |
+// day (5) | hour (5) | minute (6) | second (6), matching MySQL's on-disk
+// DATETIME2 layout — to microseconds since the Unix epoch, the unit
+// arrow.Timestamp_us expects.
+func arrowTimestamp(packed int64) arrow.Timestamp {
+	v := uint64(packed)
+	fracMicros := v & 0xFFFFFF
+	v = (v >> 24) & 0xFFFFFFFFFF // 40-bit year/month/day/hour/minute/second field
+
+	second := v & 0x3F
+	v >>= 6
+	minute := v & 0x3F
+	v >>= 6
+	hour := v & 0x1F
+	v >>= 5
+	day := v & 0x1F
+	v >>= 5
+	yearMonth := v & 0x1FFFF
+
+	year := yearMonth / 13
+	month := yearMonth % 13
+	if month == 0 || day == 0 {
+		return 0
+	}
+
+	t := time.Date(int(year), time.Month(month), int(day), int(hour), int(minute), int(second), 0, time.UTC)
+	return arrow.Timestamp(t.UnixMicro() + int64(fracMicros))
+}
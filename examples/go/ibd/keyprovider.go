@@ -0,0 +1,167 @@
+package ibd
+
+/*
+#include "ibd_reader_api.h"
+#include <stdlib.h>
+
+extern uint8_t* goFetchMasterKeyTrampoline(uint32_t id, char* uuid, uintptr_t token, size_t* out_len);
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// KeyProvider supplies InnoDB master keys on demand, letting DecryptFile's
+// keyring lookups be backed by something other than an on-disk keyring file
+// (a KMS, a secrets manager, an in-memory map for tests, ...).
+type KeyProvider interface {
+	// FetchMasterKey returns the raw master key bytes for the given key ID
+	// and server UUID, or an error if the key cannot be found.
+	FetchMasterKey(id uint32, uuid string) ([]byte, error)
+}
+
+// providerRegistry maps an opaque token passed through the C layer back to
+// the KeyProvider that should service the callback. cgo forbids passing a
+// Go pointer to Go memory through C as userdata, so callers get an integer
+// token instead.
+var (
+	providerRegistryMu sync.Mutex
+	providerRegistry   = map[C.uintptr_t]KeyProvider{}
+	nextProviderToken  C.uintptr_t
+)
+
+func registerProvider(p KeyProvider) C.uintptr_t {
+	providerRegistryMu.Lock()
+	defer providerRegistryMu.Unlock()
+	nextProviderToken++
+	token := nextProviderToken
+	providerRegistry[token] = p
+	return token
+}
+
+func unregisterProvider(token C.uintptr_t) {
+	providerRegistryMu.Lock()
+	defer providerRegistryMu.Unlock()
+	delete(providerRegistry, token)
+}
+
+//export goFetchMasterKeyTrampoline
+func goFetchMasterKeyTrampoline(id C.uint32_t, uuid *C.char, token C.uintptr_t, outLen *C.size_t) *C.uint8_t {
+	providerRegistryMu.Lock()
+	p, ok := providerRegistry[token]
+	providerRegistryMu.Unlock()
+	if !ok {
+		*outLen = 0
+		return nil
+	}
+
+	key, err := p.FetchMasterKey(uint32(id), C.GoString(uuid))
+	if err != nil || len(key) == 0 {
+		*outLen = 0
+		return nil
+	}
+
+	// C.CBytes allocates with C malloc and the Go side never frees it:
+	// ownership of the buffer passes to the C caller of this callback.
+	cKey := C.CBytes(key)
+	*outLen = C.size_t(len(key))
+	return (*C.uint8_t)(cKey)
+}
+
+// DecryptFileWithProvider decrypts inputPath to outputPath, calling back
+// into p for each master key the C reader needs instead of reading a
+// keyring file from disk. This avoids pre-staging keyring files, which
+// matters for cloud deployments where keys live in a KMS.
+func (r *Reader) DecryptFileWithProvider(in, out string, p KeyProvider) error {
+	if r.handle == nil {
+		return errors.New("reader is closed")
+	}
+	if p == nil {
+		return errors.New("p must not be nil")
+	}
+
+	token := registerProvider(p)
+	defer unregisterProvider(token)
+
+	cInput := C.CString(in)
+	cOutput := C.CString(out)
+	defer C.free(unsafe.Pointer(cInput))
+	defer C.free(unsafe.Pointer(cOutput))
+
+	result := C.ibd_decrypt_file_with_provider(
+		r.handle,
+		cInput,
+		cOutput,
+		C.ibd_key_callback_t(C.goFetchMasterKeyTrampoline),
+		token,
+	)
+	if result != Success {
+		return fmt.Errorf("decryption failed: %s (code %d)", r.GetError(), result)
+	}
+
+	return nil
+}
+
+// FileKeyProvider reads master keys from an on-disk MySQL/MariaDB keyring
+// file, the same source DecryptFile uses. It exists so callers can migrate
+// to the KeyProvider API without giving up file-based keyrings.
+type FileKeyProvider struct {
+	KeyringPath string
+}
+
+// FetchMasterKey calls into the C reader's own keyring file parser via
+// ibd_keyring_file_fetch_key, keeping the file format logic in one place.
+func (fp FileKeyProvider) FetchMasterKey(id uint32, uuid string) ([]byte, error) {
+	cPath := C.CString(fp.KeyringPath)
+	cUUID := C.CString(uuid)
+	defer C.free(unsafe.Pointer(cPath))
+	defer C.free(unsafe.Pointer(cUUID))
+
+	keyBuf := make([]byte, 512)
+	keyLen := C.size_t(len(keyBuf))
+
+	result := C.ibd_keyring_file_fetch_key(
+		cPath,
+		C.uint32_t(id),
+		cUUID,
+		(*C.uint8_t)(unsafe.Pointer(&keyBuf[0])),
+		&keyLen,
+	)
+	if result != Success {
+		return nil, fmt.Errorf("keyring file lookup failed: code %d", result)
+	}
+
+	return keyBuf[:keyLen], nil
+}
+
+// MemoryKeyProvider is an in-memory KeyProvider for tests, keyed by
+// "id:uuid".
+type MemoryKeyProvider struct {
+	Keys map[string][]byte
+}
+
+// NewMemoryKeyProvider returns an empty MemoryKeyProvider ready for Set calls.
+func NewMemoryKeyProvider() *MemoryKeyProvider {
+	return &MemoryKeyProvider{Keys: make(map[string][]byte)}
+}
+
+// Set registers the master key for the given id/uuid pair.
+func (mp *MemoryKeyProvider) Set(id uint32, uuid string, key []byte) {
+	mp.Keys[memoryKeyProviderKey(id, uuid)] = key
+}
+
+// FetchMasterKey implements KeyProvider.
+func (mp *MemoryKeyProvider) FetchMasterKey(id uint32, uuid string) ([]byte, error) {
+	key, ok := mp.Keys[memoryKeyProviderKey(id, uuid)]
+	if !ok {
+		return nil, fmt.Errorf("no key registered for id %d uuid %s", id, uuid)
+	}
+	return key, nil
+}
+
+func memoryKeyProviderKey(id uint32, uuid string) string {
+	return fmt.Sprintf("%d:%s", id, uuid)
+}
@@ -0,0 +1,68 @@
+package ibd
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// VaultKeyProvider fetches InnoDB master keys from HashiCorp Vault's Transit
+// secrets engine, addressing each key as "<KeyNamePrefix><id>-<uuid>".
+type VaultKeyProvider struct {
+	Client        *vault.Client
+	MountPath     string // e.g. "transit"
+	KeyNamePrefix string // e.g. "innodb-master-key-"
+}
+
+// FetchMasterKey implements KeyProvider by reading the raw key material out
+// of the Transit key's highest-numbered version via the "export" endpoint.
+func (vp VaultKeyProvider) FetchMasterKey(id uint32, uuid string) ([]byte, error) {
+	keyName := fmt.Sprintf("%s%d-%s", vp.KeyNamePrefix, id, uuid)
+	path := fmt.Sprintf("%s/export/encryption-key/%s", vp.MountPath, keyName)
+
+	secret, err := vp.Client.Logical().ReadWithContext(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("vault export %s: %w", keyName, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("vault export %s: no data returned", keyName)
+	}
+
+	keys, ok := secret.Data["keys"].(map[string]interface{})
+	if !ok || len(keys) == 0 {
+		return nil, fmt.Errorf("vault export %s: unexpected response shape", keyName)
+	}
+
+	// Transit's "keys" map is keyed by version number as a string, in no
+	// guaranteed order, so the highest numeric version has to be picked
+	// explicitly rather than relying on (randomized) map iteration order.
+	var latest string
+	latestVersion := -1
+	for versionStr, v := range keys {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			continue
+		}
+		if version > latestVersion {
+			latestVersion = version
+			latest = s
+		}
+	}
+	if latest == "" {
+		return nil, fmt.Errorf("vault export %s: no usable key version", keyName)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(latest)
+	if err != nil {
+		return nil, fmt.Errorf("vault export %s: decode key material: %w", keyName, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,128 @@
+package ibd
+
+/*
+#include "ibd_reader_api.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// Page types used by MariaDB/Percona-style page compression, where an
+// entire physical page is compressed and the tail hole-punched rather than
+// InnoDB's ROW_FORMAT=COMPRESSED (KEY_BLOCK_SIZE) scheme.
+const (
+	PageTypePageCompressed          uint16 = 34354
+	PageTypePageCompressedEncrypted uint16 = 34355
+)
+
+// CompressionAlgo identifies the codec a page-compressed page was written
+// with, using the same ids MariaDB stores in FIL_PAGE_FILE_FLUSH_LSN.
+type CompressionAlgo uint8
+
+const (
+	CompressionAlgoNone   CompressionAlgo = 0
+	CompressionAlgoZlib   CompressionAlgo = 1
+	CompressionAlgoLZ4    CompressionAlgo = 2
+	CompressionAlgoLZO    CompressionAlgo = 3
+	CompressionAlgoLZMA   CompressionAlgo = 4
+	CompressionAlgoBzip2  CompressionAlgo = 5
+	CompressionAlgoSnappy CompressionAlgo = 6
+)
+
+// AlgorithmName returns the human-readable name MariaDB uses for a page
+// compression algorithm id, as stored in the FIL_PAGE_FILE_FLUSH_LSN field
+// of a page-compressed page.
+func AlgorithmName(id uint8) string {
+	switch CompressionAlgo(id) {
+	case CompressionAlgoNone:
+		return "none"
+	case CompressionAlgoZlib:
+		return "zlib"
+	case CompressionAlgoLZ4:
+		return "lz4"
+	case CompressionAlgoLZO:
+		return "lzo"
+	case CompressionAlgoLZMA:
+		return "lzma"
+	case CompressionAlgoBzip2:
+		return "bzip2"
+	case CompressionAlgoSnappy:
+		return "snappy"
+	default:
+		return fmt.Sprintf("unknown(%d)", id)
+	}
+}
+
+const (
+	filPageType         = 24
+	filPageFileFlushLSN = 26
+	filPageData         = 38
+	filTrailerSize      = 8
+)
+
+// IsPageCompressedFormat reports whether page looks like a MariaDB/Percona
+// page-compressed page, based on its FIL_PAGE_TYPE.
+func IsPageCompressedFormat(page []byte) bool {
+	if len(page) < filPageData {
+		return false
+	}
+	pageType := binary.BigEndian.Uint16(page[filPageType : filPageType+2])
+	return pageType == PageTypePageCompressed || pageType == PageTypePageCompressedEncrypted
+}
+
+// DecompressPageCompressed decompresses a single MariaDB/Percona
+// page-compressed page. It reads the compression algorithm id actually
+// stored in the page's FIL_PAGE_FILE_FLUSH_LSN field and the original
+// (logical) length from FIL_PAGE_DATA, checks the detected algorithm
+// against the caller-supplied algo (a mismatch almost always means the
+// caller mis-tracked which page this is), dispatches to the matching
+// codec, restores the trailing 8-byte FIL trailer, and zero-fills the
+// hole-punched region so downstream checksum verification works against a
+// page of the expected physical size.
+func (r *Reader) DecompressPageCompressed(page []byte, algo CompressionAlgo) ([]byte, error) {
+	if r.handle == nil {
+		return nil, errors.New("reader is closed")
+	}
+	if len(page) < filPageData+filTrailerSize {
+		return nil, errors.New("page too small to be page-compressed")
+	}
+
+	detectedAlgo := CompressionAlgo(page[filPageFileFlushLSN])
+	if detectedAlgo != algo {
+		return nil, fmt.Errorf("page declares compression algorithm %s but caller passed %s",
+			AlgorithmName(uint8(detectedAlgo)), AlgorithmName(uint8(algo)))
+	}
+
+	originalLen := binary.BigEndian.Uint32(page[filPageData : filPageData+4])
+	if originalLen == 0 || int(originalLen) > len(page) {
+		return nil, fmt.Errorf("invalid original length %d in page-compressed header", originalLen)
+	}
+
+	out := make([]byte, len(page))
+
+	result := C.ibd_decompress_page_compressed(
+		r.handle,
+		(*C.uint8_t)(unsafe.Pointer(&page[0])),
+		C.size_t(len(page)),
+		C.uint8_t(detectedAlgo),
+		C.uint32_t(originalLen),
+		(*C.uint8_t)(unsafe.Pointer(&out[0])),
+	)
+	if result != Success {
+		return nil, fmt.Errorf("page-compressed decompression failed: %s (code %d)", r.GetError(), result)
+	}
+
+	// Zero-fill the hole-punched region between the original data and the
+	// restored trailer so checksum verification sees a page of the physical
+	// size it expects.
+	for i := int(originalLen); i < len(out)-filTrailerSize; i++ {
+		out[i] = 0
+	}
+
+	return out, nil
+}
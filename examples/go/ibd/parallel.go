@@ -0,0 +1,259 @@
+package ibd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ParallelConfig controls DecompressFileParallel.
+type ParallelConfig struct {
+	// Workers is the number of goroutines processing pages concurrently.
+	// Defaults to 4 if zero or negative.
+	Workers int
+	// ChunkPages is how many pages each worker claims per job.
+	ChunkPages int
+	// InOrder reassembles pages in file order before writing. When false,
+	// pages are written as workers finish them, which is faster but only
+	// safe for formats where page order doesn't matter downstream.
+	InOrder bool
+}
+
+// chunkResult is one unit of parallel work: ChunkPages worth of raw page
+// bytes read starting at startPage, alongside the decompressed output once
+// a worker has processed it.
+type chunkResult struct {
+	startPage uint32
+	raw       []byte
+	out       []byte
+	err       error
+}
+
+// readSummary reports how the sequential reader goroutine finished: how
+// many chunks it dispatched, and any real I/O error (as opposed to the
+// expected io.EOF/io.ErrUnexpectedEOF at end of file).
+type readSummary struct {
+	dispatched int
+	err        error
+}
+
+// DecompressFileParallel decompresses an IBD file using a pool of worker
+// goroutines, each owning its own ibd_reader_t handle to avoid contention in
+// the C layer. Pages are read from in sequentially and dispatched to
+// workers in ChunkPages-sized batches; when cfg.InOrder is set a single
+// writer goroutine reassembles chunks in file order before writing to out.
+func (r *Reader) DecompressFileParallel(in, out string, cfg ParallelConfig) error {
+	if r.handle == nil {
+		return fmt.Errorf("reader is closed")
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	chunkPages := cfg.ChunkPages
+	if chunkPages <= 0 {
+		chunkPages = 16
+	}
+
+	inFile, err := os.Open(in)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", in, err)
+	}
+	defer inFile.Close()
+
+	outFile, err := os.Create(out)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", out, err)
+	}
+	defer outFile.Close()
+
+	pageSize, err := detectPageSize(inFile)
+	if err != nil {
+		return fmt.Errorf("detect page size: %w", err)
+	}
+
+	jobs := make(chan chunkResult)
+	results := make(chan chunkResult, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			worker, werr := NewReader()
+			if werr != nil {
+				for job := range jobs {
+					job.err = fmt.Errorf("create worker reader: %w", werr)
+					results <- job
+				}
+				return
+			}
+			defer worker.Close()
+
+			for job := range jobs {
+				results <- decompressChunk(worker, job, pageSize)
+			}
+		}()
+	}
+
+	readDone := make(chan readSummary, 1)
+	go func() {
+		defer close(jobs)
+		buf := make([]byte, chunkPages*pageSize)
+		var pageNum uint32
+		var dispatched int
+		var readErr error
+		for {
+			n, err := inFile.ReadAt(buf, int64(pageNum)*int64(pageSize))
+			if n == 0 {
+				if err != nil && err != io.EOF {
+					readErr = err
+				}
+				break
+			}
+			raw := make([]byte, n)
+			copy(raw, buf[:n])
+			jobs <- chunkResult{startPage: pageNum, raw: raw}
+			pageNum += uint32(n / pageSize)
+			dispatched++
+			if n < len(buf) {
+				if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+					readErr = err
+				}
+				break
+			}
+		}
+		readDone <- readSummary{dispatched: dispatched, err: readErr}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var received int
+	var writeErr error
+	if cfg.InOrder {
+		received, writeErr = writeResultsInOrder(results, outFile, pageSize)
+	} else {
+		received, writeErr = writeResultsAsReady(results, outFile)
+	}
+
+	summary := <-readDone
+	if writeErr != nil {
+		return writeErr
+	}
+	if summary.err != nil {
+		return fmt.Errorf("read %s: %w", in, summary.err)
+	}
+	if received != summary.dispatched {
+		return fmt.Errorf("parallel decompress: dispatched %d chunks but processed %d", summary.dispatched, received)
+	}
+
+	return nil
+}
+
+// decompressChunk decompresses every pageSize-sized page within job.raw
+// individually (job.raw holds ChunkPages pages back to back) and
+// concatenates the results, since DecompressPage only ever decodes a
+// single page's FIL header at a time.
+func decompressChunk(worker *Reader, job chunkResult, pageSize int) chunkResult {
+	out := make([]byte, 0, len(job.raw))
+
+	for off := 0; off < len(job.raw); off += pageSize {
+		end := off + pageSize
+		if end > len(job.raw) {
+			end = len(job.raw)
+		}
+
+		decoded, _, derr := worker.DecompressPage(job.raw[off:end])
+		if derr != nil {
+			job.err = fmt.Errorf("page %d: %w", job.startPage+uint32(off/pageSize), derr)
+			return job
+		}
+		out = append(out, decoded...)
+	}
+
+	job.out = out
+	return job
+}
+
+func writeResultsInOrder(results <-chan chunkResult, out *os.File, pageSize int) (received int, err error) {
+	pending := make(map[uint32]chunkResult)
+	var firstErr error
+
+	// next tracks the lowest start page not yet written; chunks are keyed
+	// by startPage, which advances by a chunk's page count (not by 1) each
+	// time one is written, so out-of-order arrivals can be buffered until
+	// it's their turn. The file always starts at page 0, so next starts
+	// there too rather than at whichever chunk happens to arrive first.
+	var next uint32
+
+	for res := range results {
+		received++
+		if res.err != nil && firstErr == nil {
+			firstErr = res.err
+		}
+		pending[res.startPage] = res
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			if ready.err == nil {
+				if _, err := out.Write(ready.out); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+			delete(pending, next)
+			next += uint32(len(ready.raw) / pageSize)
+		}
+	}
+
+	if firstErr != nil {
+		return received, fmt.Errorf("parallel decompress: %w", firstErr)
+	}
+	return received, nil
+}
+
+func writeResultsAsReady(results <-chan chunkResult, out *os.File) (received int, err error) {
+	var firstErr error
+	for res := range results {
+		received++
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		if _, err := out.Write(res.out); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return received, fmt.Errorf("parallel decompress: %w", firstErr)
+	}
+	return received, nil
+}
+
+// detectPageSize inspects the first page of f to determine the tablespace's
+// physical page size.
+func detectPageSize(f *os.File) (int, error) {
+	const maxPageSize = 64 * 1024
+	head := make([]byte, maxPageSize)
+	n, err := f.ReadAt(head, 0)
+	if n == 0 {
+		return 0, err
+	}
+	info, err := GetPageInfo(head[:n])
+	if err != nil {
+		return 0, err
+	}
+	if info.PhysicalSize <= 0 {
+		return 0, fmt.Errorf("could not determine page size from first page")
+	}
+	return info.PhysicalSize, nil
+}
@@ -0,0 +1,244 @@
+package ibd
+
+/*
+#include "ibd_reader_api.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// IterOptions controls NewPageIterator.
+type IterOptions struct {
+	// Decrypt decrypts each page before decoding it, using the keyring
+	// parameters below (mirroring DecryptFile's signature).
+	Decrypt     bool
+	KeyringPath string
+	MasterKeyID uint32
+	ServerUUID  string
+	// Decompress decompresses each page (ROW_FORMAT=COMPRESSED) after any
+	// decryption step.
+	Decompress bool
+}
+
+// PageIterator walks the pages of a tablespace one at a time without
+// materializing the whole file in memory, so callers can stream pages from
+// object storage or apply per-page filtering.
+type PageIterator struct {
+	r        *Reader
+	src      io.ReaderAt
+	pageSize int
+	opts     IterOptions
+	pageNum  uint32
+	buf      []byte
+	err      error
+}
+
+// NewPageIterator returns an iterator over the pages read from src.
+func (r *Reader) NewPageIterator(src io.ReaderAt, pageSize int, opts IterOptions) (*PageIterator, error) {
+	if r.handle == nil {
+		return nil, errors.New("reader is closed")
+	}
+	if pageSize <= 0 {
+		return nil, errors.New("pageSize must be positive")
+	}
+	if opts.Decrypt && (opts.KeyringPath == "" || opts.ServerUUID == "") {
+		return nil, errors.New("decrypt requires KeyringPath and ServerUUID")
+	}
+
+	return &PageIterator{
+		r:        r,
+		src:      src,
+		pageSize: pageSize,
+		opts:     opts,
+		buf:      make([]byte, pageSize),
+	}, nil
+}
+
+// Next reads and decodes the next page. It returns io.EOF once the
+// underlying source is exhausted.
+func (it *PageIterator) Next() (pageNum uint32, raw []byte, decoded []byte, info *PageInfo, err error) {
+	if it.err != nil {
+		return 0, nil, nil, nil, it.err
+	}
+
+	off := int64(it.pageNum) * int64(it.pageSize)
+	n, readErr := it.src.ReadAt(it.buf, off)
+	if n == 0 && readErr != nil {
+		it.err = readErr
+		return 0, nil, nil, nil, readErr
+	}
+
+	raw = make([]byte, n)
+	copy(raw, it.buf[:n])
+	decoded = raw
+
+	if it.opts.Decrypt {
+		decoded, err = it.r.decryptPage(decoded, it.pageNum, it.opts.KeyringPath, it.opts.MasterKeyID, it.opts.ServerUUID)
+		if err != nil {
+			it.err = err
+			return 0, nil, nil, nil, fmt.Errorf("decrypt page %d: %w", it.pageNum, err)
+		}
+	}
+
+	if it.opts.Decompress {
+		var pi *PageInfo
+		decoded, pi, err = it.r.DecompressPage(decoded)
+		if err != nil {
+			it.err = err
+			return 0, nil, nil, nil, fmt.Errorf("decompress page %d: %w", it.pageNum, err)
+		}
+		info = pi
+	} else {
+		info, err = GetPageInfo(decoded)
+		if err != nil {
+			it.err = err
+			return 0, nil, nil, nil, fmt.Errorf("get page info for page %d: %w", it.pageNum, err)
+		}
+	}
+	info.PageNumber = it.pageNum
+
+	pageNum = it.pageNum
+	it.pageNum++
+
+	if readErr == io.EOF && n < it.pageSize {
+		it.err = io.EOF
+	}
+
+	return pageNum, raw, decoded, info, nil
+}
+
+// decryptPage decrypts a single page in memory via the keyring identified by
+// keyringPath/masterKeyID/serverUUID.
+func (r *Reader) decryptPage(page []byte, pageNum uint32, keyringPath string, masterKeyID uint32, serverUUID string) ([]byte, error) {
+	if r.handle == nil {
+		return nil, errors.New("reader is closed")
+	}
+	if len(page) == 0 {
+		return nil, errors.New("empty input")
+	}
+
+	cKeyring := C.CString(keyringPath)
+	cUUID := C.CString(serverUUID)
+	defer C.free(unsafe.Pointer(cKeyring))
+	defer C.free(unsafe.Pointer(cUUID))
+
+	decrypted := make([]byte, len(page))
+
+	result := C.ibd_decrypt_page(
+		r.handle,
+		(*C.uint8_t)(unsafe.Pointer(&page[0])),
+		C.size_t(len(page)),
+		C.uint32_t(pageNum),
+		cKeyring,
+		C.uint32_t(masterKeyID),
+		cUUID,
+		(*C.uint8_t)(unsafe.Pointer(&decrypted[0])),
+	)
+	if result != Success {
+		return nil, fmt.Errorf("page decryption failed: %s (code %d)", r.GetError(), result)
+	}
+
+	return decrypted, nil
+}
+
+// TransformOptions controls TransformStream.
+type TransformOptions struct {
+	PageSize    int
+	Decrypt     bool
+	KeyringPath string
+	MasterKeyID uint32
+	ServerUUID  string
+	Decompress  bool
+	// RewriteChecksum, when set, recomputes each page's FIL checksum under
+	// Algo before writing it out.
+	RewriteChecksum bool
+	Algo            ChecksumAlgo
+}
+
+// TransformStream pipelines decrypt -> decompress -> rewrite-checksum over
+// src, writing the result to dst one page at a time without ever
+// materializing the whole tablespace on disk.
+func (r *Reader) TransformStream(src io.Reader, dst io.Writer, opts TransformOptions) error {
+	if r.handle == nil {
+		return errors.New("reader is closed")
+	}
+	if opts.PageSize <= 0 {
+		return errors.New("PageSize must be positive")
+	}
+
+	buf := make([]byte, opts.PageSize)
+	var pageNum uint32
+
+	for {
+		n, readErr := io.ReadFull(src, buf)
+		if n == 0 {
+			if readErr == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("read page %d: %w", pageNum, readErr)
+		}
+
+		page := buf[:n]
+
+		var err error
+		if opts.Decrypt {
+			page, err = r.decryptPage(page, pageNum, opts.KeyringPath, opts.MasterKeyID, opts.ServerUUID)
+			if err != nil {
+				return fmt.Errorf("decrypt page %d: %w", pageNum, err)
+			}
+		}
+
+		if opts.Decompress {
+			page, _, err = r.DecompressPage(page)
+			if err != nil {
+				return fmt.Errorf("decompress page %d: %w", pageNum, err)
+			}
+		}
+
+		if opts.RewriteChecksum {
+			page, err = r.rewritePageChecksum(page, opts.Algo)
+			if err != nil {
+				return fmt.Errorf("rewrite checksum for page %d: %w", pageNum, err)
+			}
+		}
+
+		if _, err := dst.Write(page); err != nil {
+			return fmt.Errorf("write page %d: %w", pageNum, err)
+		}
+
+		pageNum++
+
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if readErr != nil {
+			return fmt.Errorf("read page %d: %w", pageNum, readErr)
+		}
+	}
+}
+
+func (r *Reader) rewritePageChecksum(page []byte, algo ChecksumAlgo) ([]byte, error) {
+	if len(page) == 0 {
+		return nil, errors.New("empty input")
+	}
+
+	out := make([]byte, len(page))
+
+	result := C.ibd_rewrite_page_checksum(
+		r.handle,
+		(*C.uint8_t)(unsafe.Pointer(&page[0])),
+		C.size_t(len(page)),
+		C.int(algo),
+		(*C.uint8_t)(unsafe.Pointer(&out[0])),
+	)
+	if result != Success {
+		return nil, fmt.Errorf("checksum rewrite failed: %s (code %d)", r.GetError(), result)
+	}
+
+	return out, nil
+}